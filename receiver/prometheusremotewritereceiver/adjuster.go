@@ -0,0 +1,236 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// defaultAdjusterGCInterval is how often idle per-series state is evicted by a
+// startTimeAdjuster when the receiver is not configured with a different
+// adjuster_gc_interval.
+const defaultAdjusterGCInterval = 5 * time.Minute
+
+// seriesState is the per-series state a startTimeAdjuster tracks across
+// requests: the timestamp of the first point seen for the series, used as its
+// StartTimestamp until a reset is detected, and the last cumulative value
+// observed, used to detect resets.
+type seriesState struct {
+	startTimestamp pcommon.Timestamp
+	lastValue      float64
+	lastSeen       time.Time
+}
+
+// startTimeAdjuster assigns a StartTimestamp to cumulative data points (Sum,
+// Histogram and ExponentialHistogram), mirroring the StartTimeMetricAdjuster /
+// initialPointAdjuster approach of the prometheus scrape receiver: the first
+// point seen for a series becomes its start time, and a cumulative value lower
+// than the one previously observed is treated as a counter reset, which moves
+// the start time forward to the point that reset it. Idle series are evicted
+// periodically so memory use tracks active series rather than every series
+// ever seen.
+type startTimeAdjuster struct {
+	mu     sync.Mutex
+	series map[uint64]*seriesState
+
+	gcInterval time.Duration
+	stopCh     chan struct{}
+
+	// startTimeMetricRegex, if set, names a gauge metric (for example
+	// process_start_time_seconds) whose value is interpreted as a Unix
+	// timestamp in seconds and used as the start time for every cumulative
+	// series of the same resource in the same request, overriding the
+	// first-seen heuristic.
+	startTimeMetricRegex *regexp.Regexp
+}
+
+// newStartTimeAdjuster creates a startTimeAdjuster. gcInterval defaults to
+// defaultAdjusterGCInterval when zero. startTimeMetricRegexPattern may be
+// empty to disable the start-time-metric override.
+func newStartTimeAdjuster(gcInterval time.Duration, startTimeMetricRegexPattern string) (*startTimeAdjuster, error) {
+	if gcInterval <= 0 {
+		gcInterval = defaultAdjusterGCInterval
+	}
+
+	var re *regexp.Regexp
+	if startTimeMetricRegexPattern != "" {
+		var err error
+		re, err = regexp.Compile(startTimeMetricRegexPattern)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &startTimeAdjuster{
+		series:               make(map[uint64]*seriesState),
+		gcInterval:           gcInterval,
+		stopCh:               make(chan struct{}),
+		startTimeMetricRegex: re,
+	}, nil
+}
+
+// Start begins periodically evicting idle series in the background.
+func (a *startTimeAdjuster) Start() {
+	go func() {
+		ticker := time.NewTicker(a.gcInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				a.gc()
+			case <-a.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops the background eviction goroutine.
+func (a *startTimeAdjuster) Shutdown() {
+	close(a.stopCh)
+}
+
+func (a *startTimeAdjuster) gc() {
+	cutoff := time.Now().Add(-a.gcInterval)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for key, s := range a.series {
+		if s.lastSeen.Before(cutoff) {
+			delete(a.series, key)
+		}
+	}
+}
+
+// Adjust sets StartTimestamp on every cumulative data point of the metrics in
+// resourceGroups, which groups the same metricCache used to build the request's
+// metrics by ResourceID (see metricIdentity.ResourceID), keyed within each
+// group by the same metricIdentity hash used to build metricCache. Grouping by
+// resource keeps a start_time_metric_regex override (e.g.
+// process_start_time_seconds) from one resource being applied to unrelated
+// resources in the same request: a single remote-write push commonly carries
+// series for many job/instance combinations, each with its own start-time
+// metric. It must be called once every series observed by the current request
+// has been folded into its metric, and before the metrics are handed to the
+// next consumer.
+func (a *startTimeAdjuster) Adjust(resourceGroups map[string]map[uint64]pmetric.Metric) {
+	for _, metricCache := range resourceGroups {
+		override, hasOverride := a.findStartTimeMetricOverride(metricCache)
+
+		for metricKey, metric := range metricCache {
+			switch metric.Type() {
+			case pmetric.MetricTypeSum:
+				a.adjustNumberDataPoints(metricKey, metric.Sum().DataPoints(), override, hasOverride)
+			case pmetric.MetricTypeHistogram:
+				a.adjustHistogramDataPoints(metricKey, metric.Histogram().DataPoints(), override, hasOverride)
+			case pmetric.MetricTypeExponentialHistogram:
+				a.adjustExponentialHistogramDataPoints(metricKey, metric.ExponentialHistogram().DataPoints(), override, hasOverride)
+			}
+		}
+	}
+}
+
+// findStartTimeMetricOverride looks for a gauge metric matching
+// startTimeMetricRegex within a single resource's metrics and, if found,
+// interprets the value of its first data point as a Unix timestamp in
+// seconds.
+func (a *startTimeAdjuster) findStartTimeMetricOverride(metricCache map[uint64]pmetric.Metric) (pcommon.Timestamp, bool) {
+	if a.startTimeMetricRegex == nil {
+		return 0, false
+	}
+
+	for _, metric := range metricCache {
+		if metric.Type() != pmetric.MetricTypeGauge || !a.startTimeMetricRegex.MatchString(metric.Name()) {
+			continue
+		}
+		dps := metric.Gauge().DataPoints()
+		if dps.Len() == 0 {
+			continue
+		}
+		seconds := dps.At(0).DoubleValue()
+		return pcommon.NewTimestampFromTime(time.Unix(0, int64(seconds*float64(time.Second)))), true
+	}
+	return 0, false
+}
+
+func (a *startTimeAdjuster) adjustNumberDataPoints(metricKey uint64, dps pmetric.NumberDataPointSlice, override pcommon.Timestamp, hasOverride bool) {
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		a.adjustPoint(metricKey, dp.Attributes(), dp.Timestamp(), dp.DoubleValue(), dp.Flags().NoRecordedValue(), override, hasOverride, dp.SetStartTimestamp)
+	}
+}
+
+func (a *startTimeAdjuster) adjustHistogramDataPoints(metricKey uint64, dps pmetric.HistogramDataPointSlice, override pcommon.Timestamp, hasOverride bool) {
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		a.adjustPoint(metricKey, dp.Attributes(), dp.Timestamp(), float64(dp.Count()), dp.Flags().NoRecordedValue(), override, hasOverride, dp.SetStartTimestamp)
+	}
+}
+
+func (a *startTimeAdjuster) adjustExponentialHistogramDataPoints(metricKey uint64, dps pmetric.ExponentialHistogramDataPointSlice, override pcommon.Timestamp, hasOverride bool) {
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		a.adjustPoint(metricKey, dp.Attributes(), dp.Timestamp(), float64(dp.Count()), dp.Flags().NoRecordedValue(), override, hasOverride, dp.SetStartTimestamp)
+	}
+}
+
+// adjustPoint resolves the StartTimestamp for a single data point, either from
+// the start-time-metric override or from this series' first-seen/reset state,
+// and applies it via setStartTimestamp. A stale point (the Prometheus
+// staleness marker, see noteStaleness) carries no real value — its
+// DoubleValue/Count is just the zero value a lower cumulative value than
+// previously observed would also produce — so it must neither be compared
+// against lastValue as a false reset nor overwrite lastValue itself; it still
+// gets the series' current startTimestamp applied, same as any other point.
+func (a *startTimeAdjuster) adjustPoint(metricKey uint64, attrs pcommon.Map, timestamp pcommon.Timestamp, value float64, stale bool, override pcommon.Timestamp, hasOverride bool, setStartTimestamp func(pcommon.Timestamp)) {
+	if hasOverride {
+		setStartTimestamp(override)
+		return
+	}
+
+	key := seriesStateKey(metricKey, attrs)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, ok := a.series[key]
+	if !ok {
+		s = &seriesState{startTimestamp: timestamp}
+		a.series[key] = s
+	} else if !stale && value < s.lastValue {
+		// A lower cumulative value than previously observed means the
+		// underlying counter (or histogram) reset, most commonly because the
+		// process restarted; start counting from the point that reset it.
+		s.startTimestamp = timestamp
+	}
+
+	if !stale {
+		s.lastValue = value
+	}
+	s.lastSeen = time.Now()
+	setStartTimestamp(s.startTimestamp)
+}
+
+// seriesStateKey computes a stable identifier for a series within a metric,
+// from the metric's identity hash and its attributes, so that two series of
+// the same metric with different label sets are tracked independently.
+func seriesStateKey(metricKey uint64, attrs pcommon.Map) uint64 {
+	parts := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		parts = append(parts, k+"="+v.AsString())
+		return true
+	})
+	sort.Strings(parts)
+
+	return xxhash.Sum64String(strconv.FormatUint(metricKey, 36) + "\xff" + strings.Join(parts, "\xff"))
+}
@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func newStartTimeMetric(name string, startSeconds float64) pmetric.Metric {
+	m := pmetric.NewMetrics().ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName(name)
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetDoubleValue(startSeconds)
+	return m
+}
+
+func newCounterMetric(name string) pmetric.Metric {
+	m := pmetric.NewMetrics().ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName(name)
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := sum.DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(100, 0)))
+	dp.SetDoubleValue(5)
+	return m
+}
+
+// TestAdjustScopesOverridePerResource reproduces two resources in the same
+// request, only one of which carries a process_start_time_seconds series: the
+// other resource's cumulative series must fall back to the first-seen
+// heuristic instead of picking up the unrelated resource's override.
+func TestAdjustScopesOverridePerResource(t *testing.T) {
+	a, err := newStartTimeAdjuster(0, "^process_start_time_seconds$")
+	require.NoError(t, err)
+
+	startTimeMetric := newStartTimeMetric("process_start_time_seconds", 50)
+	counterWithOverride := newCounterMetric("requests_total")
+	counterWithoutOverride := newCounterMetric("other_requests_total")
+
+	resourceGroups := map[string]map[uint64]pmetric.Metric{
+		"resource-a": {
+			1: startTimeMetric,
+			2: counterWithOverride,
+		},
+		"resource-b": {
+			3: counterWithoutOverride,
+		},
+	}
+
+	a.Adjust(resourceGroups)
+
+	dpA := counterWithOverride.Sum().DataPoints().At(0)
+	assert.Equal(t, pcommon.NewTimestampFromTime(time.Unix(50, 0)), dpA.StartTimestamp())
+
+	dpB := counterWithoutOverride.Sum().DataPoints().At(0)
+	assert.Equal(t, dpB.Timestamp(), dpB.StartTimestamp())
+}
+
+// TestAdjustIgnoresStalePointAsResetSource reproduces a counter series with a
+// staleness marker (see noteStaleness) landing between two real samples: the
+// stale point's unset value must not be read as a drop to zero and treated as
+// a counter reset, which would otherwise move StartTimestamp forward to the
+// stale point and corrupt every following point's start time.
+func TestAdjustIgnoresStalePointAsResetSource(t *testing.T) {
+	a, err := newStartTimeAdjuster(0, "")
+	require.NoError(t, err)
+
+	m := pmetric.NewMetrics().ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("requests_total")
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+	dp1 := sum.DataPoints().AppendEmpty()
+	dp1.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(100, 0)))
+	dp1.SetDoubleValue(5)
+
+	dp2 := sum.DataPoints().AppendEmpty()
+	dp2.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(110, 0)))
+	dp2.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+
+	dp3 := sum.DataPoints().AppendEmpty()
+	dp3.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(120, 0)))
+	dp3.SetDoubleValue(6)
+
+	a.Adjust(map[string]map[uint64]pmetric.Metric{"resource-a": {1: m}})
+
+	want := pcommon.NewTimestampFromTime(time.Unix(100, 0))
+	assert.Equal(t, want, dp1.StartTimestamp())
+	assert.Equal(t, want, dp2.StartTimestamp())
+	assert.Equal(t, want, dp3.StartTimestamp())
+}
@@ -0,0 +1,43 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+// Config defines configuration for the Prometheus remote-write receiver.
+type Config struct {
+	confighttp.ServerConfig `mapstructure:",squash"`
+
+	// EnableRemoteWriteV1 allows the receiver to accept the Prometheus Remote-Write
+	// v1 protocol (prompb.WriteRequest) in addition to v2. V1 carries no per-series
+	// metadata, so the metric type is inferred from well-known metric name suffixes.
+	// Disabled by default since v1 type inference is best-effort.
+	EnableRemoteWriteV1 bool `mapstructure:"enable_remote_write_v1"`
+
+	// DisableExemplars prevents the receiver from translating Remote-Write v2
+	// exemplars into OTLP exemplars, restoring the previous behavior of dropping
+	// them. Exemplars are translated by default.
+	DisableExemplars bool `mapstructure:"disable_exemplars"`
+
+	// StartTimeMetricRegex, analogous to the prometheus scrape receiver's
+	// option of the same name, names a gauge metric (for example
+	// process_start_time_seconds) whose value is used as the start time for
+	// every cumulative series of the same resource in a request, overriding the
+	// receiver's default first-seen/reset heuristic. Disabled when empty.
+	StartTimeMetricRegex string `mapstructure:"start_time_metric_regex"`
+
+	// AdjusterGCInterval controls how often the receiver evicts per-series
+	// state kept to detect counter resets for cumulative series. Defaults to
+	// 5 minutes when zero.
+	AdjusterGCInterval time.Duration `mapstructure:"adjuster_gc_interval"`
+}
+
+// Validate checks that the receiver configuration is valid.
+func (cfg *Config) Validate() error {
+	return nil
+}
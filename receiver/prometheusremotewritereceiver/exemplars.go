@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"encoding/hex"
+	"time"
+
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// addExemplars converts every writev2.Exemplar in exemplars whose timestamp
+// matches a data point into an OTLP exemplar on that data point. Following the
+// OpenMetrics/Prometheus convention also used by Grafana Agent and the
+// Prometheus OTLP receiver, the "trace_id" and "span_id" labels are recognized
+// and hex-decoded into the exemplar's trace and span IDs instead of being kept
+// as filtered attributes.
+func addExemplars(dest pmetric.ExemplarSlice, symbols []string, timestamp int64, exemplars []writev2.Exemplar, stats *translationStats) {
+	for _, e := range exemplars {
+		if e.Timestamp != timestamp {
+			continue
+		}
+
+		ex := dest.AppendEmpty()
+		ex.SetTimestamp(pcommon.Timestamp(e.Timestamp * int64(time.Millisecond)))
+		ex.SetDoubleValue(e.Value)
+
+		attrs := ex.FilteredAttributes()
+		for i := 0; i+1 < len(e.LabelsRefs); i += 2 {
+			name := symbolAt(symbols, e.LabelsRefs[i])
+			value := symbolAt(symbols, e.LabelsRefs[i+1])
+
+			switch name {
+			case "trace_id":
+				if traceID, err := hex.DecodeString(value); err == nil && len(traceID) == 16 {
+					ex.SetTraceID(pcommon.TraceID(traceID))
+					continue
+				}
+			case "span_id":
+				if spanID, err := hex.DecodeString(value); err == nil && len(spanID) == 8 {
+					ex.SetSpanID(pcommon.SpanID(spanID))
+					continue
+				}
+			}
+			attrs.PutStr(name, value)
+		}
+		stats.Exemplars++
+	}
+}
+
+// symbolAt safely resolves a symbol table reference, returning an empty string
+// for an out-of-bounds ref rather than panicking on malformed input.
+func symbolAt(symbols []string, ref uint32) string {
+	if int(ref) >= len(symbols) {
+		return ""
+	}
+	return symbols[ref]
+}
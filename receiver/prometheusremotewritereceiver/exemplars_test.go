@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"testing"
+
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestAddExemplarsTraceAndSpanID(t *testing.T) {
+	symbols := []string{"", "trace_id", "4bf92f3577b34da6a3ce929d0e0e4736", "span_id", "00f067aa0ba902b7", "other", "value"}
+	exemplars := []writev2.Exemplar{
+		{
+			Value:     1.5,
+			Timestamp: 1000,
+			LabelsRefs: []uint32{
+				1, 2, // trace_id
+				3, 4, // span_id
+				5, 6, // other=value
+			},
+		},
+	}
+	stats := &translationStats{}
+
+	dest := pmetric.NewExemplarSlice()
+	addExemplars(dest, symbols, 1000, exemplars, stats)
+
+	require.Equal(t, 1, dest.Len())
+	ex := dest.At(0)
+	assert.Equal(t, 1.5, ex.DoubleValue())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", ex.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", ex.SpanID().String())
+	assert.Equal(t, "value", ex.FilteredAttributes().AsRaw()["other"])
+	assert.Equal(t, 1, stats.Exemplars)
+}
+
+func TestAddExemplarsSkipsTimestampMismatch(t *testing.T) {
+	symbols := []string{""}
+	exemplars := []writev2.Exemplar{{Value: 1, Timestamp: 999}}
+	stats := &translationStats{}
+
+	dest := pmetric.NewExemplarSlice()
+	addExemplars(dest, symbols, 1000, exemplars, stats)
+
+	assert.Equal(t, 0, dest.Len())
+	assert.Equal(t, 0, stats.Exemplars)
+}
+
+func TestSymbolAtOutOfBounds(t *testing.T) {
+	assert.Equal(t, "", symbolAt([]string{"a"}, 5))
+	assert.Equal(t, "a", symbolAt([]string{"a"}, 0))
+}
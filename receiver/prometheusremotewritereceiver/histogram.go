@@ -0,0 +1,259 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+// classicHistogramPoint accumulates the _bucket/_count/_sum component series of a
+// single classic histogram sample, keyed by timestamp so that several samples of
+// the same series arriving in one request stay separate.
+type classicHistogramPoint struct {
+	count      *float64
+	sum        *float64
+	buckets    map[float64]float64 // cumulative count, keyed by the "le" upper bound
+	attributes labels.Labels       // labels shared by every component series, minus "le"
+	stale      bool                // true if any component series reported the staleness marker
+}
+
+// classicHistogramGroup accumulates every component series of a classic
+// histogram metric seen so far in a single translateV2 call, keyed by
+// timestamp (in milliseconds, as carried on the wire).
+type classicHistogramGroup struct {
+	points map[int64]*classicHistogramPoint
+}
+
+func newClassicHistogramGroup() *classicHistogramGroup {
+	return &classicHistogramGroup{points: make(map[int64]*classicHistogramPoint)}
+}
+
+func (g *classicHistogramGroup) pointAt(timestamp int64, attributes labels.Labels) *classicHistogramPoint {
+	p, ok := g.points[timestamp]
+	if !ok {
+		p = &classicHistogramPoint{buckets: make(map[float64]float64), attributes: attributes}
+		g.points[timestamp] = p
+	}
+	return p
+}
+
+// classicHistogramSuffix returns the well-known classic histogram suffix of a
+// metric name, if any.
+func classicHistogramSuffix(metricName string) string {
+	switch {
+	case hasSuffix(metricName, "_bucket"):
+		return "_bucket"
+	case hasSuffix(metricName, "_count"):
+		return "_count"
+	case hasSuffix(metricName, "_sum"):
+		return "_sum"
+	default:
+		return ""
+	}
+}
+
+func hasSuffix(s, suffix string) bool {
+	return len(s) > len(suffix) && s[len(s)-len(suffix):] == suffix
+}
+
+// addClassicHistogramSeries folds one component series of a classic histogram
+// (identified by its name suffix) into the group's per-timestamp accumulator.
+// Buckets are kept as the raw cumulative counts reported on the wire; ordering
+// and delta conversion happen once the group is finalized. samples is
+// protocol-agnostic (see rawSample) so this is shared by the v1 and v2 paths.
+func addClassicHistogramSeries(group *classicHistogramGroup, suffix string, ls labels.Labels, samples []rawSample, seenNonStale *lru.Cache[uint64, struct{}], logger *zap.Logger, stats *translationStats) {
+	attributes := stripHistogramLabels(ls)
+	key := seriesKey(ls)
+	for _, sample := range samples {
+		p := group.pointAt(sample.Timestamp, attributes)
+		if noteStaleness(seenNonStale, logger, key, sample.Value) {
+			p.stale = true
+			stats.StaleSamples++
+			stats.Samples++
+			continue
+		}
+		switch suffix {
+		case "_count":
+			v := sample.Value
+			p.count = &v
+		case "_sum":
+			v := sample.Value
+			p.sum = &v
+		case "_bucket":
+			le, err := strconv.ParseFloat(ls.Get(labels.BucketLabel), 64)
+			if err != nil {
+				continue
+			}
+			p.buckets[le] = sample.Value
+		}
+		stats.Samples++
+	}
+}
+
+// stripHistogramLabels returns the labels a classic histogram's component
+// series share, once the metric name, the "le" bucket bound, and the labels
+// that become resource/scope attributes elsewhere are removed.
+func stripHistogramLabels(ls labels.Labels) labels.Labels {
+	b := labels.NewScratchBuilder(ls.Len())
+	ls.Range(func(l labels.Label) {
+		switch l.Name {
+		case labels.MetricName, labels.BucketLabel, "instance", "job", "otel_scope_name", "otel_scope_version":
+			return
+		}
+		b.Add(l.Name, l.Value)
+	})
+	b.Sort()
+	return b.Labels()
+}
+
+// finalizeClassicHistograms converts every point accumulated in group into an
+// OTLP HistogramDataPoint, ordering buckets by their "le" upper bound and
+// treating "+Inf" as the implicit overflow bucket, per the classic histogram
+// exposition format.
+func finalizeClassicHistograms(datapoints pmetric.HistogramDataPointSlice, group *classicHistogramGroup) int {
+	converted := 0
+	for timestamp, p := range group.points {
+		dp := datapoints.AppendEmpty()
+		dp.SetTimestamp(pcommon.Timestamp(timestamp * int64(time.Millisecond)))
+
+		if p.stale {
+			dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			attrs := dp.Attributes()
+			p.attributes.Range(func(l labels.Label) {
+				attrs.PutStr(l.Name, l.Value)
+			})
+			converted++
+			continue
+		}
+
+		if p.count != nil {
+			dp.SetCount(uint64(*p.count))
+		}
+		if p.sum != nil {
+			dp.SetSum(*p.sum)
+		}
+
+		bounds := make([]float64, 0, len(p.buckets))
+		for le := range p.buckets {
+			bounds = append(bounds, le)
+		}
+		sort.Float64s(bounds)
+
+		explicitBounds := dp.ExplicitBounds()
+		bucketCounts := dp.BucketCounts()
+		var prevCumulative float64
+		for _, le := range bounds {
+			cumulative := p.buckets[le]
+			if !math.IsInf(le, 1) {
+				explicitBounds.Append(le)
+			}
+			bucketCounts.Append(uint64(cumulative - prevCumulative))
+			prevCumulative = cumulative
+		}
+
+		attrs := dp.Attributes()
+		p.attributes.Range(func(l labels.Label) {
+			attrs.PutStr(l.Name, l.Value)
+		})
+		converted++
+	}
+	return converted
+}
+
+// addNativeHistogramDatapoints converts every writev2.Histogram carried on ts
+// (Prometheus native histograms) into an OTLP ExponentialHistogramDataPoint.
+// Native histograms use the same base-2 subdivision as OTLP exponential
+// histograms, so the schema carries over directly; only the delta-encoded
+// bucket spans need decoding into the contiguous, absolute bucket counts OTLP
+// expects. A native histogram series reports its staleness marker (see
+// noteStaleness) as a plain Sample on the same TimeSeries rather than as a
+// Histogram, so ts.Samples is checked the same way the classic histogram path
+// checks it via rawSamplesFromV2.
+func addNativeHistogramDatapoints(datapoints pmetric.ExponentialHistogramDataPointSlice, ls labels.Labels, ts writev2.TimeSeries, symbols []string, disableExemplars bool, seenNonStale *lru.Cache[uint64, struct{}], logger *zap.Logger, stats *translationStats) int {
+	converted := 0
+	key := seriesKey(ls)
+	for _, sample := range rawSamplesFromV2(ts.Samples) {
+		if !noteStaleness(seenNonStale, logger, key, sample.Value) {
+			continue
+		}
+		dp := datapoints.AppendEmpty()
+		dp.SetTimestamp(pcommon.Timestamp(sample.Timestamp * int64(time.Millisecond)))
+		dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+		putHistogramSeriesLabels(dp.Attributes(), ls)
+		stats.StaleSamples++
+		stats.Samples++
+		converted++
+	}
+
+	for _, h := range ts.Histograms {
+		fh := h.ToFloatHistogram()
+
+		dp := datapoints.AppendEmpty()
+		dp.SetTimestamp(pcommon.Timestamp(h.Timestamp * int64(time.Millisecond)))
+		dp.SetScale(fh.Schema)
+		dp.SetZeroThreshold(fh.ZeroThreshold)
+		dp.SetZeroCount(uint64(fh.ZeroCount))
+		dp.SetCount(uint64(fh.Count))
+		dp.SetSum(fh.Sum)
+
+		fillExponentialBuckets(dp.Positive(), fh.PositiveSpans, fh.PositiveBuckets)
+		fillExponentialBuckets(dp.Negative(), fh.NegativeSpans, fh.NegativeBuckets)
+
+		putHistogramSeriesLabels(dp.Attributes(), ls)
+		stats.Samples++
+		converted++
+
+		if !disableExemplars {
+			addExemplars(dp.Exemplars(), symbols, h.Timestamp, ts.Exemplars, stats)
+		}
+	}
+	return converted
+}
+
+// putHistogramSeriesLabels copies a native histogram series' labels into attrs,
+// dropping the ones that become resource/scope attributes elsewhere.
+func putHistogramSeriesLabels(attrs pcommon.Map, ls labels.Labels) {
+	for _, l := range ls {
+		if l.Name == "instance" || l.Name == "job" || l.Name == labels.MetricName ||
+			l.Name == "otel_scope_name" || l.Name == "otel_scope_version" {
+			continue
+		}
+		attrs.PutStr(l.Name, l.Value)
+	}
+}
+
+// fillExponentialBuckets expands the (possibly sparse) spans reported for a
+// native histogram into OTLP's single contiguous bucket-count slice, filling
+// the gaps between spans with zero counts.
+func fillExponentialBuckets(dst pmetric.ExponentialHistogramDataPointBuckets, spans []histogram.Span, buckets []float64) {
+	if len(spans) == 0 {
+		return
+	}
+
+	dst.SetOffset(spans[0].Offset)
+	counts := dst.BucketCounts()
+	idx := 0
+	for i, span := range spans {
+		if i > 0 {
+			for gap := int32(0); gap < span.Offset; gap++ {
+				counts.Append(0)
+			}
+		}
+		for j := uint32(0); j < span.Length; j++ {
+			counts.Append(uint64(buckets[idx]))
+			idx++
+		}
+	}
+}
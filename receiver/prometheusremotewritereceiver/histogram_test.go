@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"testing"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+func TestFinalizeClassicHistograms(t *testing.T) {
+	group := newClassicHistogramGroup()
+	seenNonStale, err := lru.New[uint64, struct{}](8)
+	require.NoError(t, err)
+	logger := zap.NewNop()
+	stats := &translationStats{}
+
+	ls := labels.FromStrings(labels.MetricName, "http_request_duration_seconds_bucket", "le", "0.5")
+	addClassicHistogramSeries(group, "_bucket", ls, []rawSample{{Value: 3, Timestamp: 1000}}, seenNonStale, logger, stats)
+
+	lsCount := labels.FromStrings(labels.MetricName, "http_request_duration_seconds_count")
+	addClassicHistogramSeries(group, "_count", lsCount, []rawSample{{Value: 3, Timestamp: 1000}}, seenNonStale, logger, stats)
+
+	lsSum := labels.FromStrings(labels.MetricName, "http_request_duration_seconds_sum")
+	addClassicHistogramSeries(group, "_sum", lsSum, []rawSample{{Value: 1.5, Timestamp: 1000}}, seenNonStale, logger, stats)
+
+	datapoints := pmetric.NewHistogramDataPointSlice()
+	converted := finalizeClassicHistograms(datapoints, group)
+
+	assert.Equal(t, 1, converted)
+	require.Equal(t, 1, datapoints.Len())
+	dp := datapoints.At(0)
+	assert.Equal(t, uint64(3), dp.Count())
+	assert.Equal(t, 1.5, dp.Sum())
+	assert.Equal(t, 3, stats.Samples)
+}
+
+func TestAddNativeHistogramDatapointsEmpty(t *testing.T) {
+	ls := labels.FromStrings(labels.MetricName, "latency_seconds")
+	stats := &translationStats{}
+	seenNonStale, err := lru.New[uint64, struct{}](8)
+	require.NoError(t, err)
+	logger := zap.NewNop()
+
+	ts := writev2.TimeSeries{}
+	datapoints := pmetric.NewExponentialHistogramDataPointSlice()
+	converted := addNativeHistogramDatapoints(datapoints, ls, ts, nil, true, seenNonStale, logger, stats)
+
+	assert.Equal(t, 0, converted)
+	assert.Equal(t, 0, datapoints.Len())
+}
+
+// TestAddNativeHistogramDatapointsStaleSample reproduces a native histogram
+// series' staleness marker, which arrives as a plain Sample on the same
+// TimeSeries rather than as a Histogram: it must still produce a
+// no-recorded-value ExponentialHistogramDataPoint instead of being silently
+// dropped.
+func TestAddNativeHistogramDatapointsStaleSample(t *testing.T) {
+	ls := labels.FromStrings(labels.MetricName, "latency_seconds")
+	stats := &translationStats{}
+	seenNonStale, err := lru.New[uint64, struct{}](8)
+	require.NoError(t, err)
+	logger := zap.NewNop()
+
+	ts := writev2.TimeSeries{
+		Samples: []writev2.Sample{{Value: float64(value.StaleNaN), Timestamp: 1000}},
+	}
+	datapoints := pmetric.NewExponentialHistogramDataPointSlice()
+	converted := addNativeHistogramDatapoints(datapoints, ls, ts, nil, true, seenNonStale, logger, stats)
+
+	require.Equal(t, 1, converted)
+	require.Equal(t, 1, datapoints.Len())
+	dp := datapoints.At(0)
+	assert.True(t, dp.Flags().NoRecordedValue())
+	assert.Equal(t, 1, stats.StaleSamples)
+}
@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Type is the component type of this receiver.
+var Type = component.MustNewType("prometheusremotewrite")
+
+const (
+	// MetricsStability is the stability level of the metrics signal for this receiver.
+	MetricsStability = component.StabilityLevelAlpha
+)
+
+// telemetryBuilder holds the OpenTelemetry instruments this receiver emits
+// about its own operation, built from the collector's MeterProvider.
+type telemetryBuilder struct {
+	receiverRequests           metric.Int64Counter
+	receiverSamples            metric.Int64Counter
+	receiverTranslationErrors  metric.Int64Counter
+	receiverRMCacheEntries     metric.Int64Gauge
+	receiverTranslationLatency metric.Float64Histogram
+}
+
+// newTelemetryBuilder creates and registers this receiver's self-telemetry
+// instruments against settings.MeterProvider.
+func newTelemetryBuilder(settings component.TelemetrySettings) (*telemetryBuilder, error) {
+	meter := settings.MeterProvider.Meter("github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver")
+
+	var (
+		tb  telemetryBuilder
+		err error
+	)
+
+	tb.receiverRequests, err = meter.Int64Counter(
+		"otelcol_receiver_prometheusremotewrite_requests_total",
+		metric.WithDescription("Number of Prometheus remote-write requests received, by protocol version and outcome."),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tb.receiverSamples, err = meter.Int64Counter(
+		"otelcol_receiver_prometheusremotewrite_samples_total",
+		metric.WithDescription("Number of samples translated into OTLP data points, by metric type."),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tb.receiverTranslationErrors, err = meter.Int64Counter(
+		"otelcol_receiver_prometheusremotewrite_translation_errors_total",
+		metric.WithDescription("Number of errors encountered while translating a remote-write request, by reason."),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tb.receiverRMCacheEntries, err = meter.Int64Gauge(
+		"otelcol_receiver_prometheusremotewrite_rm_cache_entries",
+		metric.WithDescription("Number of resource metrics the receiver currently has cached."),
+		metric.WithUnit("1"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tb.receiverTranslationLatency, err = meter.Float64Histogram(
+		"otelcol_receiver_prometheusremotewrite_translation_duration",
+		metric.WithDescription("Time spent translating a remote-write request into OTLP metrics."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tb, nil
+}
+
+// addRequest records one handled request, by protocol version ("v1" or "v2")
+// and outcome ("success" or "error").
+func (tb *telemetryBuilder) addRequest(ctx context.Context, protoVersion, outcome string) {
+	tb.receiverRequests.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("proto_version", protoVersion),
+		attribute.String("outcome", outcome),
+	))
+}
+
+// addSamples records count samples translated for the given metric type
+// ("counter", "gauge", "histogram" or "summary").
+func (tb *telemetryBuilder) addSamples(ctx context.Context, count int, metricType string) {
+	if count <= 0 {
+		return
+	}
+	tb.receiverSamples.Add(ctx, int64(count), metric.WithAttributes(
+		attribute.String("type", metricType),
+	))
+}
+
+// addTranslationError records one translation error for the given reason.
+func (tb *telemetryBuilder) addTranslationError(ctx context.Context, reason string) {
+	tb.receiverTranslationErrors.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("reason", reason),
+	))
+}
+
+// recordRMCacheEntries records the current size of the receiver's resource
+// metrics cache.
+func (tb *telemetryBuilder) recordRMCacheEntries(ctx context.Context, count int) {
+	tb.receiverRMCacheEntries.Record(ctx, int64(count))
+}
+
+// recordTranslationLatency records how long a single request took to
+// translate, by protocol version.
+func (tb *telemetryBuilder) recordTranslationLatency(ctx context.Context, seconds float64, protoVersion string) {
+	tb.receiverTranslationLatency.Record(ctx, seconds, metric.WithAttributes(
+		attribute.String("proto_version", protoVersion),
+	))
+}
@@ -0,0 +1,28 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	noopmetric "go.opentelemetry.io/otel/metric/noop"
+)
+
+func TestNewTelemetryBuilder(t *testing.T) {
+	settings := component.TelemetrySettings{MeterProvider: noopmetric.NewMeterProvider()}
+
+	tb, err := newTelemetryBuilder(settings)
+	require.NoError(t, err)
+	require.NotNil(t, tb)
+
+	ctx := context.Background()
+	tb.addRequest(ctx, "v2", "success")
+	tb.addSamples(ctx, 3, "gauge")
+	tb.addTranslationError(ctx, "missing_name")
+	tb.recordRMCacheEntries(ctx, 10)
+	tb.recordTranslationLatency(ctx, 0.01, "v2")
+}
@@ -17,6 +17,7 @@ import (
 	lru "github.com/hashicorp/golang-lru/v2"
 	promconfig "github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
 	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	promremote "github.com/prometheus/prometheus/storage/remote"
 	"go.opentelemetry.io/collector/component"
@@ -26,6 +27,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
@@ -37,6 +39,26 @@ func newRemoteWriteReceiver(settings receiver.Settings, cfg *Config, nextConsume
 		return nil, fmt.Errorf("failed to create LRU cache: %w", err)
 	}
 
+	summaryCache, err := lru.New[uint64, *summaryGroup](1000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create summary LRU cache: %w", err)
+	}
+
+	seenNonStale, err := lru.New[uint64, struct{}](10000)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staleness LRU cache: %w", err)
+	}
+
+	adjuster, err := newStartTimeAdjuster(cfg.AdjusterGCInterval, cfg.StartTimeMetricRegex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create start-time adjuster: %w", err)
+	}
+
+	telemetryBuilder, err := newTelemetryBuilder(settings.TelemetrySettings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create telemetry builder: %w", err)
+	}
+
 	return &prometheusRemoteWriteReceiver{
 		settings:     settings,
 		nextConsumer: nextConsumer,
@@ -44,7 +66,11 @@ func newRemoteWriteReceiver(settings receiver.Settings, cfg *Config, nextConsume
 		server: &http.Server{
 			ReadTimeout: 60 * time.Second,
 		},
-		rmCache: cache,
+		rmCache:           cache,
+		summaryCache:      summaryCache,
+		seenNonStale:      seenNonStale,
+		startTimeAdjuster: adjuster,
+		telemetryBuilder:  telemetryBuilder,
 	}, nil
 }
 
@@ -57,7 +83,22 @@ type prometheusRemoteWriteReceiver struct {
 	wg     sync.WaitGroup
 
 	rmCache *lru.Cache[uint64, pmetric.ResourceMetrics]
-	obsrecv *receiverhelper.ObsReport
+	// summaryCache keeps the in-progress state of summaries whose quantile,
+	// _sum and _count component series may arrive across more than one
+	// remote-write request, keyed by the same metricIdentity hash used for
+	// metricCache within a single translateV2 call.
+	summaryCache *lru.Cache[uint64, *summaryGroup]
+	// seenNonStale remembers, per series (see seriesKey), whether the receiver
+	// has ever observed a non-stale sample for it, so a stale sample with no
+	// prior recorded value can be logged.
+	seenNonStale *lru.Cache[uint64, struct{}]
+	// startTimeAdjuster assigns StartTimestamp to cumulative data points and
+	// detects counter resets across requests.
+	startTimeAdjuster *startTimeAdjuster
+	// telemetryBuilder holds the instruments used to report this receiver's
+	// own operation (requests, samples, translation errors and latency).
+	telemetryBuilder *telemetryBuilder
+	obsrecv          *receiverhelper.ObsReport
 }
 
 // metricIdentity contains all the components that uniquely identify a metric
@@ -101,6 +142,8 @@ func (mi metricIdentity) Hash() uint64 {
 }
 
 func (prw *prometheusRemoteWriteReceiver) Start(ctx context.Context, host component.Host) error {
+	prw.startTimeAdjuster.Start()
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/v1/write", prw.handlePRW)
 	var err error
@@ -133,6 +176,8 @@ func (prw *prometheusRemoteWriteReceiver) Start(ctx context.Context, host compon
 }
 
 func (prw *prometheusRemoteWriteReceiver) Shutdown(ctx context.Context) error {
+	prw.startTimeAdjuster.Shutdown()
+
 	if prw.server == nil {
 		return nil
 	}
@@ -159,7 +204,12 @@ func (prw *prometheusRemoteWriteReceiver) handlePRW(w http.ResponseWriter, req *
 		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
 		return
 	}
-	if msgType != promconfig.RemoteWriteProtoMsgV2 {
+	if msgType == promconfig.RemoteWriteProtoMsgV1 && !prw.config.EnableRemoteWriteV1 {
+		prw.settings.Logger.Warn("message received using remote-write v1, which is disabled, rejecting")
+		http.Error(w, "Remote-write v1 is disabled, set enable_remote_write_v1 to accept it", http.StatusUnsupportedMediaType)
+		return
+	}
+	if msgType != promconfig.RemoteWriteProtoMsgV1 && msgType != promconfig.RemoteWriteProtoMsgV2 {
 		prw.settings.Logger.Warn("message received with unsupported proto version, rejecting")
 		http.Error(w, "Unsupported proto version", http.StatusUnsupportedMediaType)
 		return
@@ -175,19 +225,43 @@ func (prw *prometheusRemoteWriteReceiver) handlePRW(w http.ResponseWriter, req *
 		return
 	}
 
-	var prw2Req writev2.Request
-	if err = proto.Unmarshal(body, &prw2Req); err != nil {
-		prw.settings.Logger.Warn("Error decoding remote write request", zapcore.Field{Key: "error", Type: zapcore.ErrorType, Interface: err})
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	protoVersion := "v2"
+	if msgType == promconfig.RemoteWriteProtoMsgV1 {
+		protoVersion = "v1"
 	}
 
-	m, stats, err := prw.translateV2(req.Context(), &prw2Req)
+	var (
+		m     pmetric.Metrics
+		stats translationStats
+	)
+	translationStart := time.Now()
+	if msgType == promconfig.RemoteWriteProtoMsgV1 {
+		var prw1Req prompb.WriteRequest
+		if err = proto.Unmarshal(body, &prw1Req); err != nil {
+			prw.settings.Logger.Warn("Error decoding remote write request", zapcore.Field{Key: "error", Type: zapcore.ErrorType, Interface: err})
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m, stats, err = prw.translateV1(req.Context(), &prw1Req)
+	} else {
+		var prw2Req writev2.Request
+		if err = proto.Unmarshal(body, &prw2Req); err != nil {
+			prw.settings.Logger.Warn("Error decoding remote write request", zapcore.Field{Key: "error", Type: zapcore.ErrorType, Interface: err})
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m, stats, err = prw.translateV2(req.Context(), &prw2Req)
+	}
+	prw.telemetryBuilder.recordTranslationLatency(req.Context(), time.Since(translationStart).Seconds(), protoVersion)
+	prw.telemetryBuilder.recordRMCacheEntries(req.Context(), prw.rmCache.Len())
+
 	stats.SetHeaders(w)
 	if err != nil {
+		prw.telemetryBuilder.addRequest(req.Context(), protoVersion, "error")
 		http.Error(w, err.Error(), http.StatusBadRequest) // Following instructions at https://prometheus.io/docs/specs/remote_write_spec_2_0/#invalid-samples
 		return
 	}
+	prw.telemetryBuilder.addRequest(req.Context(), protoVersion, "success")
 
 	w.WriteHeader(http.StatusNoContent)
 
@@ -231,28 +305,38 @@ func (prw *prometheusRemoteWriteReceiver) parseProto(contentType string) (promco
 
 // translateV2 translates a v2 remote-write request into OTLP metrics.
 // translate is not feature complete.
-func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *writev2.Request) (pmetric.Metrics, promremote.WriteResponseStats, error) {
+func (prw *prometheusRemoteWriteReceiver) translateV2(ctx context.Context, req *writev2.Request) (pmetric.Metrics, translationStats, error) {
 	var (
 		badRequestErrors error
 		otelMetrics      = pmetric.NewMetrics()
 		labelsBuilder    = labels.NewScratchBuilder(0)
 		// More about stats: https://github.com/prometheus/docs/blob/main/docs/specs/prw/remote_write_spec_2_0.md#required-written-response-headers
-		// TODO: add histograms and exemplars to the stats. Histograms can be added after this PR be merged. Ref #39864
-		// Exemplars should be implemented to add them to the stats.
-		stats = promremote.WriteResponseStats{
-			Confirmed: true,
-		}
+		stats = translationStats{WriteResponseStats: promremote.WriteResponseStats{Confirmed: true}}
 		// The key is composed by: resource_hash:scope_name:scope_version:metric_name:unit:type
 		metricCache = make(map[uint64]pmetric.Metric)
+		// metricResourceID records, for each metricCache entry, the ResourceID it
+		// belongs to, so the start-time adjuster can be run per-resource (see
+		// startTimeAdjuster.Adjust).
+		metricResourceID = make(map[uint64]string)
+		// classicHistogramGroups accumulates the _bucket/_count/_sum component series of
+		// classic histograms across the whole request, keyed by the same metricKey as
+		// metricCache, so they can be assembled into HistogramDataPoints once every
+		// series in the request has been seen.
+		classicHistogramGroups = make(map[uint64]*classicHistogramGroup)
+		// touchedSummaries tracks, per summary metricKey, which timestamps this
+		// request contributed samples to, so only those are finalized below.
+		touchedSummaries = make(map[uint64]map[int64]struct{})
 	)
 
 	for _, ts := range req.Timeseries {
 		ls := ts.ToLabels(&labelsBuilder, req.Symbols)
 		if !ls.Has(labels.MetricName) {
 			badRequestErrors = errors.Join(badRequestErrors, errors.New("missing metric name in labels"))
+			prw.telemetryBuilder.addTranslationError(ctx, "missing_name")
 			continue
 		} else if duplicateLabel, hasDuplicate := ls.HasDuplicateLabelNames(); hasDuplicate {
 			badRequestErrors = errors.Join(badRequestErrors, fmt.Errorf("duplicate label %q in labels", duplicateLabel))
+			prw.telemetryBuilder.addTranslationError(ctx, "duplicate_label")
 			continue
 		}
 
@@ -295,13 +379,30 @@ func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *wr
 
 		scopeName, scopeVersion := prw.extractScopeInfo(ls)
 		metricName := ls.Get(labels.MetricName)
+		// Classic histograms arrive as several series sharing a base name
+		// (<name>_bucket, <name>_count, <name>_sum); they must be grouped under a
+		// single OTLP metric named after the base. Native histograms keep their
+		// name as-is since they arrive as a single series.
+		identityName := metricName
+		switch {
+		case ts.Metadata.Type == writev2.Metadata_METRIC_TYPE_HISTOGRAM && len(ts.Histograms) == 0:
+			if suffix := classicHistogramSuffix(metricName); suffix != "" {
+				identityName = metricName[:len(metricName)-len(suffix)]
+			}
+		case ts.Metadata.Type == writev2.Metadata_METRIC_TYPE_SUMMARY:
+			if suffix := summarySuffix(metricName); suffix != "" {
+				identityName = metricName[:len(metricName)-len(suffix)]
+			}
+		}
 		if ts.Metadata.UnitRef >= uint32(len(req.Symbols)) {
 			badRequestErrors = errors.Join(badRequestErrors, fmt.Errorf("unit ref %d is out of bounds of symbolsTable", ts.Metadata.UnitRef))
+			prw.telemetryBuilder.addTranslationError(ctx, "unit_ref_oob")
 			continue
 		}
 
 		if ts.Metadata.HelpRef >= uint32(len(req.Symbols)) {
 			badRequestErrors = errors.Join(badRequestErrors, fmt.Errorf("help ref %d is out of bounds of symbolsTable", ts.Metadata.HelpRef))
+			prw.telemetryBuilder.addTranslationError(ctx, "help_ref_oob")
 			continue
 		}
 
@@ -314,7 +415,7 @@ func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *wr
 			resourceID.String(), // Resource identity
 			scopeName,           // Scope name
 			scopeVersion,        // Scope version
-			metricName,          // Metric name
+			identityName,        // Metric name
 			unit,                // Unit
 			ts.Metadata.Type,    // Metric type
 		)
@@ -341,7 +442,7 @@ func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *wr
 		// If the metric does not exist, we create an empty metric and add it to the cache.
 		if !exists {
 			metric = scope.Metrics().AppendEmpty()
-			metric.SetName(metricName)
+			metric.SetName(identityName)
 			metric.SetUnit(unit)
 			metric.SetDescription(description)
 
@@ -353,12 +454,17 @@ func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *wr
 				sum.SetIsMonotonic(true)
 				sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
 			case writev2.Metadata_METRIC_TYPE_HISTOGRAM:
-				metric.SetEmptyHistogram()
+				if len(ts.Histograms) > 0 {
+					metric.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				} else {
+					metric.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				}
 			case writev2.Metadata_METRIC_TYPE_SUMMARY:
 				metric.SetEmptySummary()
 			}
 
 			metricCache[metricKey] = metric
+			metricResourceID[metricKey] = resourceID.String()
 		}
 
 		// When the new description is longer than the existing one, we should update the metric description.
@@ -368,20 +474,88 @@ func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *wr
 		}
 
 		// Otherwise, we append the samples to the existing metric.
+		samplesBefore := stats.Samples
 		switch ts.Metadata.Type {
 		case writev2.Metadata_METRIC_TYPE_GAUGE:
-			addNumberDatapoints(metric.Gauge().DataPoints(), ls, ts, &stats)
+			addNumberDatapoints(metric.Gauge().DataPoints(), ls, ts, req.Symbols, prw.config.DisableExemplars, prw.seenNonStale, prw.settings.Logger, &stats)
+			prw.telemetryBuilder.addSamples(ctx, stats.Samples-samplesBefore, "gauge")
 		case writev2.Metadata_METRIC_TYPE_COUNTER:
-			addNumberDatapoints(metric.Sum().DataPoints(), ls, ts, &stats)
+			addNumberDatapoints(metric.Sum().DataPoints(), ls, ts, req.Symbols, prw.config.DisableExemplars, prw.seenNonStale, prw.settings.Logger, &stats)
+			prw.telemetryBuilder.addSamples(ctx, stats.Samples-samplesBefore, "counter")
 		case writev2.Metadata_METRIC_TYPE_HISTOGRAM:
-			addHistogramDatapoints(metric.Histogram().DataPoints(), ls, ts)
+			if len(ts.Histograms) > 0 {
+				stats.Histograms += addNativeHistogramDatapoints(metric.ExponentialHistogram().DataPoints(), ls, ts, req.Symbols, prw.config.DisableExemplars, prw.seenNonStale, prw.settings.Logger, &stats)
+			} else {
+				group, ok := classicHistogramGroups[metricKey]
+				if !ok {
+					group = newClassicHistogramGroup()
+					classicHistogramGroups[metricKey] = group
+				}
+				addClassicHistogramSeries(group, classicHistogramSuffix(metricName), ls, rawSamplesFromV2(ts.Samples), prw.seenNonStale, prw.settings.Logger, &stats)
+			}
+			prw.telemetryBuilder.addSamples(ctx, stats.Samples-samplesBefore, "histogram")
 		case writev2.Metadata_METRIC_TYPE_SUMMARY:
-			addSummaryDatapoints(metric.Summary().DataPoints(), ls, ts)
+			group, ok := prw.summaryCache.Get(metricKey)
+			if !ok {
+				group = newSummaryGroup()
+				prw.summaryCache.Add(metricKey, group)
+			}
+			touched, ok := touchedSummaries[metricKey]
+			if !ok {
+				touched = make(map[int64]struct{})
+				touchedSummaries[metricKey] = touched
+			}
+			addSummarySeries(group, summarySuffix(metricName), ls, rawSamplesFromV2(ts.Samples), touched, prw.seenNonStale, prw.settings.Logger, &stats)
+			prw.telemetryBuilder.addSamples(ctx, stats.Samples-samplesBefore, "summary")
 		default:
 			badRequestErrors = errors.Join(badRequestErrors, fmt.Errorf("unsupported metric type %q for metric %q", ts.Metadata.Type, metricName))
+			prw.telemetryBuilder.addTranslationError(ctx, "unsupported_type")
 		}
 	}
 
+	// Classic histograms are accumulated across the whole request because their
+	// _bucket/_count/_sum component series can arrive in any order; only once every
+	// series has been seen can they be assembled into HistogramDataPoints.
+	for metricKey, group := range classicHistogramGroups {
+		metric, ok := metricCache[metricKey]
+		if !ok {
+			continue
+		}
+		stats.Histograms += finalizeClassicHistograms(metric.Histogram().DataPoints(), group)
+	}
+
+	// Summaries are finalized from the persistent summaryCache: only the
+	// timestamps this request actually touched are emitted, since a summary's
+	// component series may still be completed by a future request.
+	for metricKey, touched := range touchedSummaries {
+		metric, ok := metricCache[metricKey]
+		if !ok {
+			continue
+		}
+		group, ok := prw.summaryCache.Get(metricKey)
+		if !ok {
+			continue
+		}
+		finalizeSummaries(metric.Summary().DataPoints(), group, touched)
+	}
+
+	// Prometheus cumulative series carry no start timestamp; assign one from
+	// per-series first-seen/reset tracking before handing the metrics off.
+	// Grouped by resource so a start_time_metric_regex override from one
+	// resource's series (e.g. process_start_time_seconds) never applies to an
+	// unrelated resource in the same request.
+	resourceGroups := make(map[string]map[uint64]pmetric.Metric)
+	for metricKey, metric := range metricCache {
+		resourceID := metricResourceID[metricKey]
+		group, ok := resourceGroups[resourceID]
+		if !ok {
+			group = make(map[uint64]pmetric.Metric)
+			resourceGroups[resourceID] = group
+		}
+		group[metricKey] = metric
+	}
+	prw.startTimeAdjuster.Adjust(resourceGroups)
+
 	return otelMetrics, stats, badRequestErrors
 }
 
@@ -403,14 +577,21 @@ func parseJobAndInstance(dest pcommon.Map, job, instance string) {
 }
 
 // addNumberDatapoints adds the labels to the datapoints attributes.
-func addNumberDatapoints(datapoints pmetric.NumberDataPointSlice, ls labels.Labels, ts writev2.TimeSeries, stats *promremote.WriteResponseStats) {
+func addNumberDatapoints(datapoints pmetric.NumberDataPointSlice, ls labels.Labels, ts writev2.TimeSeries, symbols []string, disableExemplars bool, seenNonStale *lru.Cache[uint64, struct{}], logger *zap.Logger, stats *translationStats) {
 	// Add samples from the timeseries
+	key := seriesKey(ls)
 	for _, sample := range ts.Samples {
 		dp := datapoints.AppendEmpty()
 		dp.SetStartTimestamp(pcommon.Timestamp(ts.CreatedTimestamp * int64(time.Millisecond)))
 		// Set timestamp in nanoseconds (Prometheus uses milliseconds)
 		dp.SetTimestamp(pcommon.Timestamp(sample.Timestamp * int64(time.Millisecond)))
-		dp.SetDoubleValue(sample.Value)
+
+		if noteStaleness(seenNonStale, logger, key, sample.Value) {
+			dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			stats.StaleSamples++
+		} else {
+			dp.SetDoubleValue(sample.Value)
+		}
 
 		attributes := dp.Attributes()
 		for _, l := range ls {
@@ -422,15 +603,11 @@ func addNumberDatapoints(datapoints pmetric.NumberDataPointSlice, ls labels.Labe
 			attributes.PutStr(l.Name, l.Value)
 		}
 		stats.Samples++
-	}
-}
 
-func addSummaryDatapoints(_ pmetric.SummaryDataPointSlice, _ labels.Labels, _ writev2.TimeSeries) {
-	// TODO: Implement this function
-}
-
-func addHistogramDatapoints(_ pmetric.HistogramDataPointSlice, _ labels.Labels, _ writev2.TimeSeries) {
-	// TODO: Implement this function
+		if !disableExemplars {
+			addExemplars(dp.Exemplars(), symbols, sample.Timestamp, ts.Exemplars, stats)
+		}
+	}
 }
 
 // extractScopeInfo extracts the scope name and version from the labels. If the labels do not contain the scope name/version,
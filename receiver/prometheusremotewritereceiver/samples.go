@@ -0,0 +1,38 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+)
+
+// rawSample is the sample shape shared by Remote-Write v1 (prompb.Sample) and
+// v2 (writev2.Sample), so that classic histogram and summary component
+// accumulation (see addClassicHistogramSeries, addSummarySeries) can be reused
+// by both protocol versions instead of duplicated per version.
+type rawSample struct {
+	Timestamp int64
+	Value     float64
+}
+
+// rawSamplesFromV2 converts the samples of a Remote-Write v2 time series into
+// the protocol-agnostic rawSample shape.
+func rawSamplesFromV2(samples []writev2.Sample) []rawSample {
+	out := make([]rawSample, len(samples))
+	for i, s := range samples {
+		out[i] = rawSample{Timestamp: s.Timestamp, Value: s.Value}
+	}
+	return out
+}
+
+// rawSamplesFromV1 converts the samples of a Remote-Write v1 time series into
+// the protocol-agnostic rawSample shape.
+func rawSamplesFromV1(samples []prompb.Sample) []rawSample {
+	out := make([]rawSample, len(samples))
+	for i, s := range samples {
+		out[i] = rawSample{Timestamp: s.Timestamp, Value: s.Value}
+	}
+	return out
+}
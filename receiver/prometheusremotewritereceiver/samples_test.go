@@ -0,0 +1,22 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawSamplesFromV2(t *testing.T) {
+	got := rawSamplesFromV2([]writev2.Sample{{Value: 1.5, Timestamp: 1000}})
+	assert.Equal(t, []rawSample{{Timestamp: 1000, Value: 1.5}}, got)
+}
+
+func TestRawSamplesFromV1(t *testing.T) {
+	got := rawSamplesFromV1([]prompb.Sample{{Value: 2.5, Timestamp: 2000}})
+	assert.Equal(t, []rawSample{{Timestamp: 2000, Value: 2.5}}, got)
+}
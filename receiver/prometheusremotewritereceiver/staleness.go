@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"github.com/cespare/xxhash/v2"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"go.uber.org/zap"
+)
+
+// seriesKey computes a stable identifier for an individual Prometheus series
+// (as opposed to metricIdentity, which identifies the OTLP metric the series
+// belongs to) so staleness can be tracked per series.
+func seriesKey(ls labels.Labels) uint64 {
+	return xxhash.Sum64String(ls.String())
+}
+
+// noteStaleness records whether a sample for the series identified by key was
+// the Prometheus staleness marker (see value.IsStaleNaN), and logs a debug
+// message each time a stale sample arrives for a series the receiver has
+// never seen a non-stale value for. seenNonStale persists across requests so
+// that this detection works regardless of which request a series' samples
+// land in.
+func noteStaleness(seenNonStale *lru.Cache[uint64, struct{}], logger *zap.Logger, key uint64, sampleValue float64) bool {
+	if !value.IsStaleNaN(sampleValue) {
+		seenNonStale.Add(key, struct{}{})
+		return false
+	}
+
+	if _, ok := seenNonStale.Get(key); !ok {
+		logger.Debug("received stale sample for a series with no prior recorded value")
+	}
+	return true
+}
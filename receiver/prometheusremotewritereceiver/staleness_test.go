@@ -0,0 +1,40 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"testing"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNoteStaleness(t *testing.T) {
+	seenNonStale, err := lru.New[uint64, struct{}](8)
+	require.NoError(t, err)
+	logger := zap.NewNop()
+	key := seriesKey(labels.FromStrings(labels.MetricName, "up"))
+
+	assert.False(t, noteStaleness(seenNonStale, logger, key, 1))
+	assert.True(t, noteStaleness(seenNonStale, logger, key, float64(value.StaleNaN)))
+}
+
+func TestNoteStalenessNoPriorValue(t *testing.T) {
+	seenNonStale, err := lru.New[uint64, struct{}](8)
+	require.NoError(t, err)
+	logger := zap.NewNop()
+	key := seriesKey(labels.FromStrings(labels.MetricName, "up"))
+
+	assert.True(t, noteStaleness(seenNonStale, logger, key, float64(value.StaleNaN)))
+}
+
+func TestSeriesKeyStableForSameLabels(t *testing.T) {
+	a := labels.FromStrings(labels.MetricName, "up", "job", "x")
+	b := labels.FromStrings(labels.MetricName, "up", "job", "x")
+	assert.Equal(t, seriesKey(a), seriesKey(b))
+}
@@ -0,0 +1,20 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	promremote "github.com/prometheus/prometheus/storage/remote"
+)
+
+// translationStats extends the upstream Remote-Write response stats with
+// counters this receiver tracks for its own observability but that have no
+// corresponding response header in the Remote-Write spec.
+type translationStats struct {
+	promremote.WriteResponseStats
+
+	// StaleSamples counts samples whose value was the Prometheus staleness
+	// marker (see value.IsStaleNaN) and were therefore translated into a
+	// no-recorded-value data point instead of a numeric one.
+	StaleSamples int
+}
@@ -0,0 +1,241 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"strconv"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/prometheus/prometheus/model/labels"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+// summaryPoint accumulates the <name>, <name>_sum and <name>_count component
+// series of a single Prometheus summary sample, keyed by timestamp.
+type summaryPoint struct {
+	count      *float64
+	sum        *float64
+	quantiles  map[float64]float64 // quantile -> value
+	attributes labels.Labels       // labels shared by every component series, minus "quantile"
+	stale      bool                // true if any component series reported the staleness marker
+	seq        int64               // generation counter, see summaryGroup.order
+}
+
+// maxPendingSummaryPoints bounds how many incomplete points a single
+// summaryGroup keeps waiting for their _count/_sum component series, so a
+// series whose _count or _sum never arrives (e.g. dropped by a relabeling
+// rule) can't grow the group without bound for the life of the receiver. The
+// oldest pending point is evicted, as-is, once the limit is reached.
+const maxPendingSummaryPoints = 1000
+
+// summaryGroup accumulates every component series of a summary metric seen so
+// far, keyed by timestamp (in milliseconds, as carried on the wire). Unlike
+// classicHistogramGroup, a summaryGroup is kept on the receiver across requests
+// (see prometheusRemoteWriteReceiver.summaryCache) since a summary's quantile,
+// _sum and _count series are not guaranteed to land in the same remote-write
+// request.
+type summaryGroup struct {
+	points map[int64]*summaryPoint
+	// order lists the (timestamp, seq) of every point added, oldest first,
+	// so the oldest pending one can be evicted once maxPendingSummaryPoints
+	// is reached. A point finalizeSummaries completes and deletes from
+	// points is not removed from order immediately — that would require an
+	// O(n) scan per finalize — so order is periodically compacted back down
+	// to just the still-pending entries instead, keeping it amortized O(1)
+	// per point while still bounded. seq disambiguates a timestamp that is
+	// completed/evicted and later reused by a new point (e.g. a remote-write
+	// client replaying its WAL after a reconnect): without it, a stale order
+	// entry for the old point would match the new point at the same
+	// timestamp and could cause it to be evicted out of true oldest-first
+	// order.
+	order   []summaryPointRef
+	nextSeq int64
+}
+
+// summaryPointRef identifies one generation of a summaryPoint within order.
+type summaryPointRef struct {
+	timestamp int64
+	seq       int64
+}
+
+func newSummaryGroup() *summaryGroup {
+	return &summaryGroup{points: make(map[int64]*summaryPoint)}
+}
+
+// stillPending reports whether ref still refers to the live point at its
+// timestamp, as opposed to a stale entry left behind by a point that has
+// since been finalized (or evicted) and possibly replaced by a new one.
+func (g *summaryGroup) stillPending(ref summaryPointRef) bool {
+	p, ok := g.points[ref.timestamp]
+	return ok && p.seq == ref.seq
+}
+
+func (g *summaryGroup) pointAt(timestamp int64, attributes labels.Labels) *summaryPoint {
+	p, ok := g.points[timestamp]
+	if ok {
+		return p
+	}
+
+	if len(g.order) >= 2*maxPendingSummaryPoints {
+		g.compactOrder()
+	}
+
+	if len(g.points) >= maxPendingSummaryPoints {
+		// order may still list refs finalizeSummaries already removed from
+		// points, or that have been superseded by a newer point at the same
+		// timestamp; skip those until an actual eviction is made.
+		for len(g.order) > 0 {
+			oldest := g.order[0]
+			g.order = g.order[1:]
+			if g.stillPending(oldest) {
+				delete(g.points, oldest.timestamp)
+				break
+			}
+		}
+	}
+
+	seq := g.nextSeq
+	g.nextSeq++
+	p = &summaryPoint{quantiles: make(map[float64]float64), attributes: attributes, seq: seq}
+	g.points[timestamp] = p
+	g.order = append(g.order, summaryPointRef{timestamp: timestamp, seq: seq})
+	return p
+}
+
+// compactOrder drops the refs in order that finalizeSummaries has already
+// completed and removed from points (or that a newer point at the same
+// timestamp has superseded), so order's size tracks the number of
+// still-pending points instead of every point ever seen.
+func (g *summaryGroup) compactOrder() {
+	kept := g.order[:0]
+	for _, ref := range g.order {
+		if g.stillPending(ref) {
+			kept = append(kept, ref)
+		}
+	}
+	g.order = kept
+}
+
+// summarySuffix returns the well-known summary suffix of a metric name, if any.
+// A summary's quantile series keep the bare metric name.
+func summarySuffix(metricName string) string {
+	switch {
+	case hasSuffix(metricName, "_count"):
+		return "_count"
+	case hasSuffix(metricName, "_sum"):
+		return "_sum"
+	default:
+		return ""
+	}
+}
+
+// addSummarySeries folds one component series of a summary (the bare name
+// carrying a "quantile" label, or a _sum/_count series) into the group's
+// per-timestamp accumulator, and tracks which timestamps were touched by this
+// call so the caller can finalize only the points this request contributed to.
+// samples is protocol-agnostic (see rawSample) so this is shared by the v1 and
+// v2 paths.
+func addSummarySeries(group *summaryGroup, suffix string, ls labels.Labels, samples []rawSample, touched map[int64]struct{}, seenNonStale *lru.Cache[uint64, struct{}], logger *zap.Logger, stats *translationStats) {
+	attributes := stripSummaryLabels(ls)
+	key := seriesKey(ls)
+	for _, sample := range samples {
+		p := group.pointAt(sample.Timestamp, attributes)
+		if noteStaleness(seenNonStale, logger, key, sample.Value) {
+			p.stale = true
+			touched[sample.Timestamp] = struct{}{}
+			stats.StaleSamples++
+			stats.Samples++
+			continue
+		}
+		switch suffix {
+		case "_count":
+			v := sample.Value
+			p.count = &v
+		case "_sum":
+			v := sample.Value
+			p.sum = &v
+		default:
+			if q, err := strconv.ParseFloat(ls.Get(labels.QuantileLabel), 64); err == nil {
+				p.quantiles[q] = sample.Value
+			}
+		}
+		touched[sample.Timestamp] = struct{}{}
+		stats.Samples++
+	}
+}
+
+// stripSummaryLabels returns the labels a summary's component series share,
+// once the metric name, the "quantile" label, and the labels that become
+// resource/scope attributes elsewhere are removed.
+func stripSummaryLabels(ls labels.Labels) labels.Labels {
+	b := labels.NewScratchBuilder(ls.Len())
+	ls.Range(func(l labels.Label) {
+		switch l.Name {
+		case labels.MetricName, labels.QuantileLabel, "instance", "job", "otel_scope_name", "otel_scope_version":
+			return
+		}
+		b.Add(l.Name, l.Value)
+	})
+	b.Sort()
+	return b.Labels()
+}
+
+// finalizeSummaries converts the points of group touched by the current
+// request into OTLP SummaryDataPoints, one per unique timestamp, with one
+// QuantileValue per observed quantile label. A point is only finalized, and
+// removed from the group, once it is complete (both its _count and _sum
+// component series have been seen) or stale; a summary's quantile series
+// carry no count of how many quantiles to expect, so completeness of those is
+// not checked. An incomplete point is left in the group so a later request
+// carrying the rest of its component series can complete it, instead of being
+// emitted (and re-created, duplicated) half-finished.
+func finalizeSummaries(datapoints pmetric.SummaryDataPointSlice, group *summaryGroup, touched map[int64]struct{}) int {
+	converted := 0
+	for timestamp := range touched {
+		p, ok := group.points[timestamp]
+		if !ok {
+			continue
+		}
+		if !p.stale && (p.count == nil || p.sum == nil) {
+			continue
+		}
+		delete(group.points, timestamp)
+
+		dp := datapoints.AppendEmpty()
+		dp.SetTimestamp(pcommon.Timestamp(timestamp * int64(time.Millisecond)))
+
+		if p.stale {
+			dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			attrs := dp.Attributes()
+			p.attributes.Range(func(l labels.Label) {
+				attrs.PutStr(l.Name, l.Value)
+			})
+			converted++
+			continue
+		}
+
+		if p.count != nil {
+			dp.SetCount(uint64(*p.count))
+		}
+		if p.sum != nil {
+			dp.SetSum(*p.sum)
+		}
+
+		for q, v := range p.quantiles {
+			qv := dp.QuantileValues().AppendEmpty()
+			qv.SetQuantile(q)
+			qv.SetValue(v)
+		}
+
+		attrs := dp.Attributes()
+		p.attributes.Range(func(l labels.Label) {
+			attrs.PutStr(l.Name, l.Value)
+		})
+		converted++
+	}
+	return converted
+}
@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"testing"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+// TestFinalizeSummariesAcrossRequests reproduces a summary's _count arriving
+// in one request and its _sum/quantile arriving in a later one: the first
+// request must not emit an incomplete point, and the second must emit exactly
+// one complete one.
+func TestFinalizeSummariesAcrossRequests(t *testing.T) {
+	group := newSummaryGroup()
+	seenNonStale, err := lru.New[uint64, struct{}](8)
+	require.NoError(t, err)
+	logger := zap.NewNop()
+	stats := &translationStats{}
+
+	// Request 1: only _count arrives.
+	touched1 := make(map[int64]struct{})
+	lsCount := labels.FromStrings(labels.MetricName, "request_latency_seconds_count")
+	addSummarySeries(group, "_count", lsCount, []rawSample{{Value: 5, Timestamp: 1000}}, touched1, seenNonStale, logger, stats)
+
+	datapoints1 := pmetric.NewSummaryDataPointSlice()
+	converted1 := finalizeSummaries(datapoints1, group, touched1)
+	assert.Equal(t, 0, converted1)
+	assert.Equal(t, 0, datapoints1.Len())
+
+	// Request 2: _sum and the quantile arrive.
+	touched2 := make(map[int64]struct{})
+	lsSum := labels.FromStrings(labels.MetricName, "request_latency_seconds_sum")
+	addSummarySeries(group, "_sum", lsSum, []rawSample{{Value: 1, Timestamp: 1000}}, touched2, seenNonStale, logger, stats)
+	lsQuantile := labels.FromStrings(labels.MetricName, "request_latency_seconds", "quantile", "0.5")
+	addSummarySeries(group, "", lsQuantile, []rawSample{{Value: 0.2, Timestamp: 1000}}, touched2, seenNonStale, logger, stats)
+
+	datapoints2 := pmetric.NewSummaryDataPointSlice()
+	converted2 := finalizeSummaries(datapoints2, group, touched2)
+	require.Equal(t, 1, converted2)
+	require.Equal(t, 1, datapoints2.Len())
+	dp := datapoints2.At(0)
+	assert.Equal(t, uint64(5), dp.Count())
+	assert.Equal(t, 1.0, dp.Sum())
+	require.Equal(t, 1, dp.QuantileValues().Len())
+}
+
+// TestSummaryGroupEvictsOldestWhenPendingLimitReached reproduces a summary
+// whose _count/_sum component series never arrive for a growing set of
+// timestamps: the group must not grow without bound.
+func TestSummaryGroupEvictsOldestWhenPendingLimitReached(t *testing.T) {
+	group := newSummaryGroup()
+	seenNonStale, err := lru.New[uint64, struct{}](8)
+	require.NoError(t, err)
+	logger := zap.NewNop()
+	stats := &translationStats{}
+
+	lsQuantile := labels.FromStrings(labels.MetricName, "request_latency_seconds", "quantile", "0.5")
+	touched := make(map[int64]struct{})
+	for ts := int64(0); ts < maxPendingSummaryPoints+10; ts++ {
+		addSummarySeries(group, "", lsQuantile, []rawSample{{Value: 0.2, Timestamp: ts}}, touched, seenNonStale, logger, stats)
+	}
+
+	assert.LessOrEqual(t, len(group.points), maxPendingSummaryPoints)
+	// The oldest timestamps should have been evicted in favor of the newest.
+	_, hasOldest := group.points[0]
+	assert.False(t, hasOldest)
+	_, hasNewest := group.points[maxPendingSummaryPoints+9]
+	assert.True(t, hasNewest)
+}
+
+// TestSummaryGroupOrderDoesNotGrowUnboundedWhenHealthy reproduces a
+// long-running, healthy summary series whose points complete (and are
+// deleted from points by finalizeSummaries) as fast as they arrive: order
+// must not grow without bound just because points stays small.
+func TestSummaryGroupOrderDoesNotGrowUnboundedWhenHealthy(t *testing.T) {
+	group := newSummaryGroup()
+	seenNonStale, err := lru.New[uint64, struct{}](8)
+	require.NoError(t, err)
+	logger := zap.NewNop()
+	stats := &translationStats{}
+	datapoints := pmetric.NewSummaryDataPointSlice()
+
+	lsCount := labels.FromStrings(labels.MetricName, "request_latency_seconds_count")
+	lsSum := labels.FromStrings(labels.MetricName, "request_latency_seconds_sum")
+
+	const samples = 5 * maxPendingSummaryPoints
+	for ts := int64(0); ts < samples; ts++ {
+		touched := make(map[int64]struct{})
+		addSummarySeries(group, "_count", lsCount, []rawSample{{Value: 1, Timestamp: ts}}, touched, seenNonStale, logger, stats)
+		addSummarySeries(group, "_sum", lsSum, []rawSample{{Value: 1, Timestamp: ts}}, touched, seenNonStale, logger, stats)
+		finalizeSummaries(datapoints, group, touched)
+	}
+
+	assert.Equal(t, samples, datapoints.Len())
+	assert.Empty(t, group.points)
+	assert.Less(t, len(group.order), 2*maxPendingSummaryPoints)
+}
+
+// TestSummaryGroupReusedTimestampIsNotEvictedByStaleOrderEntry reproduces a
+// timestamp that completes and is finalized, then is seen again (e.g. a
+// remote-write client replaying its WAL after a reconnect): the stale order
+// entry left behind by the first point must not cause the second, still
+// pending, point to be evicted when eviction or compaction later reaches it.
+func TestSummaryGroupReusedTimestampIsNotEvictedByStaleOrderEntry(t *testing.T) {
+	group := newSummaryGroup()
+	seenNonStale, err := lru.New[uint64, struct{}](8)
+	require.NoError(t, err)
+	logger := zap.NewNop()
+	stats := &translationStats{}
+	datapoints := pmetric.NewSummaryDataPointSlice()
+
+	lsCount := labels.FromStrings(labels.MetricName, "request_latency_seconds_count")
+	lsSum := labels.FromStrings(labels.MetricName, "request_latency_seconds_sum")
+
+	// First occurrence of ts=0 completes and is finalized, leaving a stale
+	// entry for it at the front of order.
+	touched := make(map[int64]struct{})
+	addSummarySeries(group, "_count", lsCount, []rawSample{{Value: 1, Timestamp: 0}}, touched, seenNonStale, logger, stats)
+	addSummarySeries(group, "_sum", lsSum, []rawSample{{Value: 1, Timestamp: 0}}, touched, seenNonStale, logger, stats)
+	finalizeSummaries(datapoints, group, touched)
+	require.Empty(t, group.points)
+
+	// ts=0 is replayed, but only its _count arrives so far: a new, distinct
+	// point that must stay pending.
+	replay := make(map[int64]struct{})
+	addSummarySeries(group, "_count", lsCount, []rawSample{{Value: 1, Timestamp: 0}}, replay, seenNonStale, logger, stats)
+	require.Contains(t, group.points, int64(0))
+
+	// Fill the group with enough distinct, complete timestamps to force a
+	// compaction pass over the stale ts=0 entry. Each of them is finalized as
+	// it arrives, so points never reaches maxPendingSummaryPoints and no FIFO
+	// eviction runs.
+	for ts := int64(1); ts <= 2*maxPendingSummaryPoints; ts++ {
+		t2 := make(map[int64]struct{})
+		addSummarySeries(group, "_count", lsCount, []rawSample{{Value: 1, Timestamp: ts}}, t2, seenNonStale, logger, stats)
+		addSummarySeries(group, "_sum", lsSum, []rawSample{{Value: 1, Timestamp: ts}}, t2, seenNonStale, logger, stats)
+		finalizeSummaries(datapoints, group, t2)
+	}
+
+	// The replayed ts=0 point is still incomplete and must not have been
+	// silently evicted by the stale order entry left by its first occurrence.
+	assert.Contains(t, group.points, int64(0))
+}
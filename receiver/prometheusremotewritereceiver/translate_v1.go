@@ -0,0 +1,326 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+	promremote "github.com/prometheus/prometheus/storage/remote"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
+)
+
+// v1Family tracks what a Remote-Write v1 metric family looks like across all of
+// the series observed for it in a single request, so the family's OTLP metric
+// type can be inferred once every member has been seen. v1 series carry no
+// metadata, unlike v2, so this is the best we can do short of a scrape-style
+// symbol table.
+type v1Family struct {
+	hasBucket   bool
+	hasQuantile bool
+}
+
+// classifyV1Series inspects every time series in the request up front and
+// groups them by base metric name, recording enough shape information to later
+// infer each family's metric type from its suffixes. This mirrors the
+// `storage/remote/otlptranslator` heuristics used by the Prometheus OTLP
+// receiver, which faces the same lack of metadata when bridging classic
+// Prometheus exposition into OTLP.
+func classifyV1Series(series []prompb.TimeSeries) map[string]*v1Family {
+	families := make(map[string]*v1Family)
+	for _, ts := range series {
+		ls := promLabelsToLabels(ts.Labels)
+		name := ls.Get(labels.MetricName)
+		base, isBucket := strings.CutSuffix(name, "_bucket")
+		if !isBucket {
+			base = strings.TrimSuffix(strings.TrimSuffix(name, "_sum"), "_count")
+		}
+		f := families[base]
+		if f == nil {
+			f = &v1Family{}
+			families[base] = f
+		}
+		if isBucket {
+			f.hasBucket = true
+		}
+		if ls.Has("quantile") {
+			f.hasQuantile = true
+		}
+	}
+	return families
+}
+
+// metricTypeAndBaseNameV1 infers the OTLP-equivalent metric type and the base
+// metric name (suffix stripped, for histograms and summaries) for a v1 series
+// from its name and the shape of its family, since v1 carries no metadata to
+// tell us directly.
+func metricTypeAndBaseNameV1(metricName string, ls labels.Labels, families map[string]*v1Family) (writev2.Metadata_MetricType, string) {
+	if base, ok := strings.CutSuffix(metricName, "_bucket"); ok {
+		return writev2.Metadata_METRIC_TYPE_HISTOGRAM, base
+	}
+	if base, ok := strings.CutSuffix(metricName, "_sum"); ok {
+		if f := families[base]; f != nil && f.hasBucket {
+			return writev2.Metadata_METRIC_TYPE_HISTOGRAM, base
+		}
+		return writev2.Metadata_METRIC_TYPE_SUMMARY, base
+	}
+	if base, ok := strings.CutSuffix(metricName, "_count"); ok {
+		if f := families[base]; f != nil && f.hasBucket {
+			return writev2.Metadata_METRIC_TYPE_HISTOGRAM, base
+		}
+		return writev2.Metadata_METRIC_TYPE_SUMMARY, base
+	}
+	if ls.Has("quantile") {
+		return writev2.Metadata_METRIC_TYPE_SUMMARY, metricName
+	}
+	if strings.HasSuffix(metricName, "_total") {
+		return writev2.Metadata_METRIC_TYPE_COUNTER, metricName
+	}
+	return writev2.Metadata_METRIC_TYPE_GAUGE, metricName
+}
+
+// promLabelsToLabels converts Remote-Write v1 labels, which are plain
+// name/value pairs with no symbol table, into the labels.Labels type shared
+// with the v2 translation path.
+func promLabelsToLabels(pl []prompb.Label) labels.Labels {
+	lbls := make([]labels.Label, len(pl))
+	for i, l := range pl {
+		lbls[i] = labels.Label{Name: l.Name, Value: l.Value}
+	}
+	return labels.New(lbls...)
+}
+
+// translateV1 translates a Remote-Write v1 request into OTLP metrics. Unlike
+// translateV2, v1 time series carry no per-series metadata, so the metric type
+// has to be inferred from the metric name and the shape of its family (see
+// classifyV1Series). It shares the rmCache and metricIdentity plumbing with
+// the v2 path so that v1 and v2 senders writing to the same job/instance merge
+// into the same resource.
+func (prw *prometheusRemoteWriteReceiver) translateV1(ctx context.Context, req *prompb.WriteRequest) (pmetric.Metrics, translationStats, error) {
+	var (
+		badRequestErrors error
+		otelMetrics      = pmetric.NewMetrics()
+		stats            = translationStats{WriteResponseStats: promremote.WriteResponseStats{Confirmed: true}}
+		metricCache      = make(map[uint64]pmetric.Metric)
+		families         = classifyV1Series(req.Timeseries)
+		// metricResourceID records, for each metricCache entry, the ResourceID it
+		// belongs to, so the start-time adjuster can be run per-resource; see
+		// translateV2's field of the same name.
+		metricResourceID = make(map[uint64]string)
+		// classicHistogramGroups accumulates the _bucket/_count/_sum component series of
+		// classic histograms across the whole request; see translateV2's field of the
+		// same name.
+		classicHistogramGroups = make(map[uint64]*classicHistogramGroup)
+		// touchedSummaries tracks, per summary metricKey, which timestamps this
+		// request contributed samples to, so only those are finalized below. Summary
+		// state itself lives in the receiver's summaryCache, shared with the v2 path.
+		touchedSummaries = make(map[uint64]map[int64]struct{})
+	)
+
+	for _, ts := range req.Timeseries {
+		ls := promLabelsToLabels(ts.Labels)
+		if !ls.Has(labels.MetricName) {
+			badRequestErrors = errors.Join(badRequestErrors, errors.New("missing metric name in labels"))
+			prw.telemetryBuilder.addTranslationError(ctx, "missing_name")
+			continue
+		} else if duplicateLabel, hasDuplicate := ls.HasDuplicateLabelNames(); hasDuplicate {
+			badRequestErrors = errors.Join(badRequestErrors, fmt.Errorf("duplicate label %q in labels", duplicateLabel))
+			prw.telemetryBuilder.addTranslationError(ctx, "duplicate_label")
+			continue
+		}
+
+		var rm pmetric.ResourceMetrics
+		hashedLabels := xxhash.Sum64String(ls.Get("job") + string([]byte{'\xff'}) + ls.Get("instance"))
+		existingRM, ok := prw.rmCache.Get(hashedLabels)
+		if ok {
+			rm = existingRM
+		} else {
+			rm = otelMetrics.ResourceMetrics().AppendEmpty()
+			parseJobAndInstance(rm.Resource().Attributes(), ls.Get("job"), ls.Get("instance"))
+			prw.rmCache.Add(hashedLabels, rm)
+		}
+
+		scopeName, scopeVersion := prw.extractScopeInfo(ls)
+		metricName := ls.Get(labels.MetricName)
+		metricType, baseName := metricTypeAndBaseNameV1(metricName, ls, families)
+
+		resourceID := identity.OfResource(rm.Resource())
+		metricIdentity := createMetricIdentity(
+			resourceID.String(),
+			scopeName,
+			scopeVersion,
+			baseName,
+			"", // v1 carries no unit metadata
+			metricType,
+		)
+		metricKey := metricIdentity.Hash()
+
+		var scope pmetric.ScopeMetrics
+		var foundScope bool
+		for i := 0; i < rm.ScopeMetrics().Len(); i++ {
+			s := rm.ScopeMetrics().At(i)
+			if s.Scope().Name() == scopeName && s.Scope().Version() == scopeVersion {
+				scope = s
+				foundScope = true
+				break
+			}
+		}
+		if !foundScope {
+			scope = rm.ScopeMetrics().AppendEmpty()
+			scope.Scope().SetName(scopeName)
+			scope.Scope().SetVersion(scopeVersion)
+		}
+
+		metric, exists := metricCache[metricKey]
+		if !exists {
+			metric = scope.Metrics().AppendEmpty()
+			metric.SetName(baseName)
+
+			switch metricType {
+			case writev2.Metadata_METRIC_TYPE_GAUGE:
+				metric.SetEmptyGauge()
+			case writev2.Metadata_METRIC_TYPE_COUNTER:
+				sum := metric.SetEmptySum()
+				sum.SetIsMonotonic(true)
+				sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			case writev2.Metadata_METRIC_TYPE_HISTOGRAM:
+				metric.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			case writev2.Metadata_METRIC_TYPE_SUMMARY:
+				metric.SetEmptySummary()
+			}
+
+			metricCache[metricKey] = metric
+			metricResourceID[metricKey] = resourceID.String()
+		}
+
+		samplesBefore := stats.Samples
+		switch metricType {
+		case writev2.Metadata_METRIC_TYPE_GAUGE:
+			addNumberDatapointsV1(metric, metricType, ls, ts, prw.seenNonStale, prw.settings.Logger, &stats)
+			prw.telemetryBuilder.addSamples(ctx, stats.Samples-samplesBefore, "gauge")
+		case writev2.Metadata_METRIC_TYPE_COUNTER:
+			addNumberDatapointsV1(metric, metricType, ls, ts, prw.seenNonStale, prw.settings.Logger, &stats)
+			prw.telemetryBuilder.addSamples(ctx, stats.Samples-samplesBefore, "counter")
+		case writev2.Metadata_METRIC_TYPE_HISTOGRAM:
+			group, ok := classicHistogramGroups[metricKey]
+			if !ok {
+				group = newClassicHistogramGroup()
+				classicHistogramGroups[metricKey] = group
+			}
+			addClassicHistogramSeries(group, classicHistogramSuffix(metricName), ls, rawSamplesFromV1(ts.Samples), prw.seenNonStale, prw.settings.Logger, &stats)
+			prw.telemetryBuilder.addSamples(ctx, stats.Samples-samplesBefore, "histogram")
+		case writev2.Metadata_METRIC_TYPE_SUMMARY:
+			group, ok := prw.summaryCache.Get(metricKey)
+			if !ok {
+				group = newSummaryGroup()
+				prw.summaryCache.Add(metricKey, group)
+			}
+			touched, ok := touchedSummaries[metricKey]
+			if !ok {
+				touched = make(map[int64]struct{})
+				touchedSummaries[metricKey] = touched
+			}
+			addSummarySeries(group, summarySuffix(metricName), ls, rawSamplesFromV1(ts.Samples), touched, prw.seenNonStale, prw.settings.Logger, &stats)
+			prw.telemetryBuilder.addSamples(ctx, stats.Samples-samplesBefore, "summary")
+		default:
+			badRequestErrors = errors.Join(badRequestErrors, fmt.Errorf("unsupported metric type %q for metric %q", metricType, metricName))
+			prw.telemetryBuilder.addTranslationError(ctx, "unsupported_type")
+		}
+	}
+
+	// Classic histograms are accumulated across the whole request because their
+	// _bucket/_count/_sum component series can arrive in any order; only once every
+	// series has been seen can they be assembled into HistogramDataPoints.
+	for metricKey, group := range classicHistogramGroups {
+		metric, ok := metricCache[metricKey]
+		if !ok {
+			continue
+		}
+		stats.Histograms += finalizeClassicHistograms(metric.Histogram().DataPoints(), group)
+	}
+
+	// Summaries are finalized from the persistent summaryCache: only the
+	// timestamps this request actually touched are emitted, since a summary's
+	// component series may still be completed by a future request (from either
+	// protocol version, since the cache is keyed by metricIdentity, not by
+	// protocol).
+	for metricKey, touched := range touchedSummaries {
+		metric, ok := metricCache[metricKey]
+		if !ok {
+			continue
+		}
+		group, ok := prw.summaryCache.Get(metricKey)
+		if !ok {
+			continue
+		}
+		finalizeSummaries(metric.Summary().DataPoints(), group, touched)
+	}
+
+	// Prometheus cumulative series carry no start timestamp; assign one from
+	// per-series first-seen/reset tracking before handing the metrics off.
+	// Grouped by resource for the same reason as translateV2.
+	resourceGroups := make(map[string]map[uint64]pmetric.Metric)
+	for metricKey, metric := range metricCache {
+		resourceID := metricResourceID[metricKey]
+		group, ok := resourceGroups[resourceID]
+		if !ok {
+			group = make(map[uint64]pmetric.Metric)
+			resourceGroups[resourceID] = group
+		}
+		group[metricKey] = metric
+	}
+	prw.startTimeAdjuster.Adjust(resourceGroups)
+
+	return otelMetrics, stats, badRequestErrors
+}
+
+// addNumberDatapointsV1 appends the samples of a Remote-Write v1 gauge or
+// counter series to the metric's datapoints. It mirrors addNumberDatapoints,
+// except that it sets no StartTimestamp, since v1 carries no created
+// timestamp, and translates no exemplars.
+func addNumberDatapointsV1(metric pmetric.Metric, metricType writev2.Metadata_MetricType, ls labels.Labels, ts prompb.TimeSeries, seenNonStale *lru.Cache[uint64, struct{}], logger *zap.Logger, stats *translationStats) {
+	var datapoints pmetric.NumberDataPointSlice
+	switch metricType {
+	case writev2.Metadata_METRIC_TYPE_COUNTER:
+		datapoints = metric.Sum().DataPoints()
+	default:
+		datapoints = metric.Gauge().DataPoints()
+	}
+
+	key := seriesKey(ls)
+	for _, sample := range ts.Samples {
+		dp := datapoints.AppendEmpty()
+		dp.SetTimestamp(pcommon.Timestamp(sample.Timestamp * int64(time.Millisecond)))
+
+		if noteStaleness(seenNonStale, logger, key, sample.Value) {
+			dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			stats.StaleSamples++
+		} else {
+			dp.SetDoubleValue(sample.Value)
+		}
+
+		attributes := dp.Attributes()
+		for _, l := range ls {
+			if l.Name == "instance" || l.Name == "job" ||
+				l.Name == labels.MetricName ||
+				l.Name == "otel_scope_name" || l.Name == "otel_scope_version" {
+				continue
+			}
+			attributes.PutStr(l.Name, l.Value)
+		}
+		stats.Samples++
+	}
+}
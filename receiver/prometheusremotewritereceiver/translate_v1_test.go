@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"context"
+	"testing"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver"
+	noopmetric "go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+)
+
+func newTestReceiverV1(t *testing.T) *prometheusRemoteWriteReceiver {
+	t.Helper()
+	rmCache, err := lru.New[uint64, pmetric.ResourceMetrics](1000)
+	require.NoError(t, err)
+	summaryCache, err := lru.New[uint64, *summaryGroup](1000)
+	require.NoError(t, err)
+	seenNonStale, err := lru.New[uint64, struct{}](1000)
+	require.NoError(t, err)
+	adjuster, err := newStartTimeAdjuster(0, "")
+	require.NoError(t, err)
+	telemetryBuilder, err := newTelemetryBuilder(component.TelemetrySettings{MeterProvider: noopmetric.NewMeterProvider()})
+	require.NoError(t, err)
+
+	return &prometheusRemoteWriteReceiver{
+		settings:          receiver.Settings{},
+		rmCache:           rmCache,
+		summaryCache:      summaryCache,
+		seenNonStale:      seenNonStale,
+		startTimeAdjuster: adjuster,
+		telemetryBuilder:  telemetryBuilder,
+	}
+}
+
+func label(name, value string) prompb.Label {
+	return prompb.Label{Name: name, Value: value}
+}
+
+func TestTranslateV1ClassicHistogram(t *testing.T) {
+	prw := newTestReceiverV1(t)
+	prw.settings.Logger = zap.NewNop()
+
+	req := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:  []prompb.Label{label("__name__", "request_duration_seconds_bucket"), label("le", "0.5")},
+				Samples: []prompb.Sample{{Value: 3, Timestamp: 1000}},
+			},
+			{
+				Labels:  []prompb.Label{label("__name__", "request_duration_seconds_count")},
+				Samples: []prompb.Sample{{Value: 3, Timestamp: 1000}},
+			},
+			{
+				Labels:  []prompb.Label{label("__name__", "request_duration_seconds_sum")},
+				Samples: []prompb.Sample{{Value: 1.5, Timestamp: 1000}},
+			},
+		},
+	}
+
+	metrics, stats, err := prw.translateV1(context.Background(), req)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	rm := metrics.ResourceMetrics().At(0)
+	require.Equal(t, 1, rm.ScopeMetrics().At(0).Metrics().Len())
+	metric := rm.ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "request_duration_seconds", metric.Name())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, metric.Histogram().AggregationTemporality())
+	require.Equal(t, 1, metric.Histogram().DataPoints().Len())
+	assert.Equal(t, uint64(3), metric.Histogram().DataPoints().At(0).Count())
+	assert.Equal(t, 3, stats.Samples)
+}
+
+func TestTranslateV1Summary(t *testing.T) {
+	prw := newTestReceiverV1(t)
+	prw.settings.Logger = zap.NewNop()
+
+	req := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:  []prompb.Label{label("__name__", "request_latency_seconds"), label("quantile", "0.5")},
+				Samples: []prompb.Sample{{Value: 0.2, Timestamp: 1000}},
+			},
+			{
+				Labels:  []prompb.Label{label("__name__", "request_latency_seconds_count")},
+				Samples: []prompb.Sample{{Value: 5, Timestamp: 1000}},
+			},
+			{
+				Labels:  []prompb.Label{label("__name__", "request_latency_seconds_sum")},
+				Samples: []prompb.Sample{{Value: 1, Timestamp: 1000}},
+			},
+		},
+	}
+
+	metrics, stats, err := prw.translateV1(context.Background(), req)
+	require.NoError(t, err)
+
+	rm := metrics.ResourceMetrics().At(0)
+	metric := rm.ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "request_latency_seconds", metric.Name())
+	require.Equal(t, 1, metric.Summary().DataPoints().Len())
+	dp := metric.Summary().DataPoints().At(0)
+	assert.Equal(t, uint64(5), dp.Count())
+	assert.Equal(t, 1.0, dp.Sum())
+	require.Equal(t, 1, dp.QuantileValues().Len())
+	assert.Equal(t, 3, stats.Samples)
+}
+
+func TestTranslateV1StaleGaugeSample(t *testing.T) {
+	prw := newTestReceiverV1(t)
+	prw.settings.Logger = zap.NewNop()
+
+	req := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:  []prompb.Label{label("__name__", "up")},
+				Samples: []prompb.Sample{{Value: float64(value.StaleNaN), Timestamp: 1000}},
+			},
+		},
+	}
+
+	metrics, stats, err := prw.translateV1(context.Background(), req)
+	require.NoError(t, err)
+
+	rm := metrics.ResourceMetrics().At(0)
+	metric := rm.ScopeMetrics().At(0).Metrics().At(0)
+	require.Equal(t, 1, metric.Gauge().DataPoints().Len())
+	dp := metric.Gauge().DataPoints().At(0)
+	assert.True(t, dp.Flags().NoRecordedValue())
+	assert.Equal(t, 1, stats.StaleSamples)
+}
+
+func TestTranslateV1SetsStartTimestampOnCounters(t *testing.T) {
+	prw := newTestReceiverV1(t)
+	prw.settings.Logger = zap.NewNop()
+
+	req := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			{
+				Labels:  []prompb.Label{label("__name__", "http_requests_total")},
+				Samples: []prompb.Sample{{Value: 5, Timestamp: 1000}},
+			},
+		},
+	}
+
+	metrics, _, err := prw.translateV1(context.Background(), req)
+	require.NoError(t, err)
+
+	rm := metrics.ResourceMetrics().At(0)
+	metric := rm.ScopeMetrics().At(0).Metrics().At(0)
+	require.Equal(t, 1, metric.Sum().DataPoints().Len())
+	dp := metric.Sum().DataPoints().At(0)
+	assert.Equal(t, dp.Timestamp(), dp.StartTimestamp())
+}